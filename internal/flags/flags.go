@@ -0,0 +1,96 @@
+// Package flags adapts the urfave/cli v1 help output and flag plumbing
+// to the category-grouped, alphabetically-sorted style used by
+// geth/quorum, since v1 has no built-in notion of flag categories.
+package flags
+
+import (
+	"sort"
+
+	"github.com/urfave/cli"
+)
+
+// Uncategorized is the bucket flags fall into when no category was
+// registered for them.
+const Uncategorized = "MISC"
+
+// categories maps a flag name to the category it should be grouped
+// under in `--help` output. Populate it via Register before the app
+// runs.
+var categories = map[string]string{}
+
+// Register assigns name to category for help-output grouping.
+// Subsequent calls overwrite a previous registration for the same name.
+func Register(category string, names ...string) {
+	for _, name := range names {
+		categories[name] = category
+	}
+}
+
+// CategorizedFlagsFromApp groups app's global flags by their registered
+// category, with each category's flags sorted alphabetically by name.
+// The map itself is unordered; use SortedCategories to iterate its
+// categories in alphabetical order.
+func CategorizedFlagsFromApp(app *cli.App) map[string][]cli.Flag {
+	grouped := make(map[string][]cli.Flag)
+	for _, f := range app.Flags {
+		cat := categories[f.GetName()]
+		if cat == "" {
+			cat = Uncategorized
+		}
+		grouped[cat] = append(grouped[cat], f)
+	}
+	for _, fs := range grouped {
+		sort.Slice(fs, func(i, j int) bool { return fs[i].GetName() < fs[j].GetName() })
+	}
+	return grouped
+}
+
+// SortedCategories returns the keys of a CategorizedFlagsFromApp result
+// in alphabetical order, so callers can iterate deterministically.
+func SortedCategories(grouped map[string][]cli.Flag) []string {
+	names := make([]string, 0, len(grouped))
+	for name := range grouped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MigrateGlobalFlags copies every global flag value set on ctx down into
+// ctx's own flag set, so subcommands can read parent flags (e.g.
+// --influxdb.endpoint) via ctx.String(name) instead of
+// ctx.GlobalString(name). urfave/cli v1 does not do this automatically
+// for flags declared on the root App rather than the invoked Command.
+//
+// This relies on every global flag being declared in app.Flags (that's
+// the only way a Context can enumerate "what global flags exist" in v1)
+// and on the target Command re-declaring any flag it wants migrated into
+// its own flag set (Set fails on a name the Command didn't register).
+func MigrateGlobalFlags(ctx *cli.Context) {
+	var migrate func(*cli.Context)
+	migrate = func(c *cli.Context) {
+		for _, name := range c.GlobalFlagNames() {
+			if ctx.IsSet(name) {
+				continue
+			}
+			if c.GlobalIsSet(name) {
+				ctx.Set(name, c.GlobalString(name))
+			}
+		}
+		if parent := c.Parent(); parent != nil {
+			migrate(parent)
+		}
+	}
+	migrate(ctx)
+}
+
+// Wrap adapts action to run MigrateGlobalFlags against its Context
+// first, so action (and anything it calls) can read a flag declared at
+// the App level with ctx.String(name) instead of having to fall back to
+// ctx.GlobalString(name) itself.
+func Wrap(action cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		MigrateGlobalFlags(c)
+		return action(c)
+	}
+}
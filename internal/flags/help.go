@@ -0,0 +1,78 @@
+package flags
+
+import (
+	"io"
+	"sort"
+
+	"github.com/urfave/cli"
+)
+
+// AppHelpTemplate replaces cli.AppHelpTemplate with one that lists the
+// app's commands in their slice order (which Init sorts alphabetically)
+// and groups global flags by the categories registered via Register.
+var AppHelpTemplate = `NAME:
+   {{.Name}}{{if .Usage}} - {{.Usage}}{{end}}
+
+USAGE:
+   {{.HelpName}} [global options] command [command options] [arguments...]
+
+COMMANDS:
+   {{range .Commands}}{{.Name}}{{with .ShortName}}, {{.}}{{end}}{{ "\t" }}{{.Usage}}
+   {{end}}{{if .Flags}}
+{{range $category, $flags := categorizedFlags .}}{{$category}}:
+   {{range $flags}}{{.}}
+   {{end}}
+{{end}}{{end}}`
+
+// CommandHelpTemplate replaces cli.CommandHelpTemplate so a single
+// command's flags are grouped the same way as the app's global flags.
+var CommandHelpTemplate = `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}}{{if .VisibleFlags}} [command options]{{end}} {{.ArgsUsage}}
+{{if .Flags}}
+{{range $category, $flags := categorizedCommandFlags .}}{{$category}}:
+   {{range $flags}}{{.}}
+   {{end}}
+{{end}}{{end}}`
+
+// categorizedFlags is a helper exposed to AppHelpTemplate via
+// cli.HelpPrinter's FuncMap; it returns CategorizedFlagsFromApp's map,
+// which text/template's range visits in sorted key order.
+func categorizedFlags(app *cli.App) map[string][]cli.Flag {
+	return CategorizedFlagsFromApp(app)
+}
+
+// categorizedCommandFlags mirrors categorizedFlags for a single Command.
+func categorizedCommandFlags(cmd cli.Command) map[string][]cli.Flag {
+	grouped := make(map[string][]cli.Flag)
+	for _, f := range cmd.Flags {
+		cat := categories[f.GetName()]
+		if cat == "" {
+			cat = Uncategorized
+		}
+		grouped[cat] = append(grouped[cat], f)
+	}
+	for _, fs := range grouped {
+		sort.Slice(fs, func(i, j int) bool { return fs[i].GetName() < fs[j].GetName() })
+	}
+	return grouped
+}
+
+// Init installs the templates above as the package-wide defaults used
+// by cli.ShowAppHelp / cli.ShowCommandHelp, and sorts app.Commands
+// alphabetically in place.
+func Init(app *cli.App) {
+	sort.Slice(app.Commands, func(i, j int) bool { return app.Commands[i].Name < app.Commands[j].Name })
+
+	cli.AppHelpTemplate = AppHelpTemplate
+	cli.CommandHelpTemplate = CommandHelpTemplate
+	cli.HelpPrinter = func(w io.Writer, templ string, data interface{}) {
+		funcMap := map[string]interface{}{
+			"categorizedFlags":        categorizedFlags,
+			"categorizedCommandFlags": categorizedCommandFlags,
+		}
+		cli.HelpPrinterCustom(w, templ, data, funcMap)
+	}
+}
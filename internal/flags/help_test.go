@@ -0,0 +1,145 @@
+package flags
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli"
+)
+
+// goldenApp builds a minimal app mirroring vendatta's flag categories,
+// so the golden file stays small and stable.
+func goldenApp() *cli.App {
+	Register("USAGE ANALYSIS", "since")
+	Register("METRICS", "pushgateway", "influxdb.endpoint")
+	Register("OUTPUT", "format")
+	Register("DEBUG", "verbose")
+
+	app := cli.NewApp()
+	app.Name = "vendatta"
+	app.HelpName = "vendatta"
+	app.Flags = []cli.Flag{
+		cli.StringFlag{Name: "since", Usage: "Only include events since this duration ago"},
+		cli.StringFlag{Name: "pushgateway", Usage: "Prometheus Pushgateway URL"},
+		cli.StringFlag{Name: "influxdb.endpoint", Usage: "InfluxDB API endpoint"},
+		cli.StringFlag{Name: "format", Usage: "Output format: text|json"},
+		cli.IntFlag{Name: "verbose", Usage: "Verbosity level"},
+	}
+	app.Commands = []cli.Command{
+		{Name: "usage", Usage: "Show usage statistics and metrics"},
+	}
+	return app
+}
+
+// renderCategories flattens CategorizedFlagsFromApp into deterministic
+// "CATEGORY: name, name" lines, in the same category and flag order
+// AppHelpTemplate renders them in, without depending on cli's own flag
+// string formatting (which is free to change its placeholder/default text).
+func renderCategories(app *cli.App) string {
+	grouped := CategorizedFlagsFromApp(app)
+	var b strings.Builder
+	for _, category := range SortedCategories(grouped) {
+		names := make([]string, len(grouped[category]))
+		for i, f := range grouped[category] {
+			names[i] = f.GetName()
+		}
+		fmt.Fprintf(&b, "%s: %s\n", category, strings.Join(names, ", "))
+	}
+	return b.String()
+}
+
+func TestCategorizedFlagsGolden(t *testing.T) {
+	app := goldenApp()
+	got := renderCategories(app)
+
+	golden := "testdata/usage_help.golden"
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("flag categorization mismatch:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+// TestAppHelpRendersCategorizedFlags actually renders `vendatta --help`
+// through the installed AppHelpTemplate/cli.HelpPrinter, so a broken
+// template (bad syntax, wrong section order, a category silently
+// dropped) fails the test instead of only the internal
+// CategorizedFlagsFromApp map being checked.
+func TestAppHelpRendersCategorizedFlags(t *testing.T) {
+	app := goldenApp()
+	var buf bytes.Buffer
+	app.Writer = &buf
+	Init(app)
+
+	ctx := cli.NewContext(app, flag.NewFlagSet("vendatta", flag.ContinueOnError), nil)
+	if err := cli.ShowAppHelp(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+
+	golden := "testdata/app_help.golden"
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("rendered --help mismatch:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestCommandsSortedAlphabetically(t *testing.T) {
+	app := goldenApp()
+	app.Commands = append(app.Commands, cli.Command{Name: "benchmark"}, cli.Command{Name: "daemon"})
+	Init(app)
+
+	var names []string
+	for _, c := range app.Commands {
+		names = append(names, c.Name)
+	}
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("expected commands sorted alphabetically, got %v", names)
+	}
+}
+
+func TestMigrateGlobalFlags(t *testing.T) {
+	app := cli.NewApp()
+	// MigrateGlobalFlags discovers which names are global via
+	// c.App.Flags (urfave/cli v1 has no other way to ask a Context "what
+	// global flags exist"), so the flag under test must be declared
+	// there, exactly as NewApp in pkg/cli declares the real global flags.
+	app.Flags = []cli.Flag{cli.StringFlag{Name: "influxdb.endpoint"}}
+
+	globalSet := flag.NewFlagSet("global", flag.ContinueOnError)
+	globalSet.String("influxdb.endpoint", "", "")
+	globalSet.Parse([]string{"--influxdb.endpoint", "http://localhost:8086"})
+	parent := cli.NewContext(app, globalSet, nil)
+
+	localSet := flag.NewFlagSet("local", flag.ContinueOnError)
+	localSet.String("influxdb.endpoint", "", "")
+	ctx := cli.NewContext(app, localSet, parent)
+
+	MigrateGlobalFlags(ctx)
+
+	if got := ctx.String("influxdb.endpoint"); got != "http://localhost:8086" {
+		t.Errorf("MigrateGlobalFlags: got %q, want %q", got, "http://localhost:8086")
+	}
+}
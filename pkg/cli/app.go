@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"github.com/IniZio/vendatta-config/internal/flags"
+	vlog "github.com/IniZio/vendatta-config/pkg/log"
+	"github.com/IniZio/vendatta-config/pkg/metrics"
+	"github.com/IniZio/vendatta-config/pkg/metrics/influxdb"
+	"github.com/urfave/cli"
+)
+
+// registerFlagCategories assigns every global flag to the category it
+// should be grouped under in `--help` output.
+func registerFlagCategories() {
+	flags.Register("USAGE ANALYSIS", "since", "alpha")
+	flags.Register("METRICS", metricsFlagNames()...)
+	flags.Register("OUTPUT", "format")
+	flags.Register("DEBUG", "verbose", "log-format", "log-file")
+}
+
+func metricsFlagNames() []string {
+	names := []string{"pushgateway", "listen-address"}
+	for _, f := range influxdb.Flags {
+		names = append(names, f.GetName())
+	}
+	return names
+}
+
+// before composes the app's startup hooks: build the logger first so
+// later hooks (and every command) can use it, then start the InfluxDB
+// reporter if it was configured.
+func before(reg *metrics.Registry) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		logger, err := vlog.New(vlog.ConfigFromContext(c))
+		if err != nil {
+			return err
+		}
+		c.App.Metadata["logger"] = logger
+		c.App.Metadata["metrics"] = reg
+		return influxdb.Before(reg)(c)
+	}
+}
+
+// NewApp builds the vendatta command tree: usage querying/reporting,
+// the background daemon, and the Prometheus /metrics endpoint, all
+// sharing reg so `metrics serve`, the InfluxDB reporter and
+// --pushgateway observe the same populated registry. Callers run the
+// result with (*cli.App).Run(os.Args).
+func NewApp(reg *metrics.Registry) *cli.App {
+	registerFlagCategories()
+
+	app := &cli.App{
+		Name:     "vendatta",
+		Usage:    `Vendatta CLI for productivity optimization and analytics`,
+		Flags:    append(append(append([]cli.Flag{}, vlog.Flags...), influxdb.Flags...), metrics.Flags...),
+		Metadata: map[string]interface{}{},
+		Before:   before(reg),
+		After:    influxdb.After,
+		Commands: []cli.Command{
+			{
+				Name:    "usage",
+				Aliases: []string{"u"},
+				Usage:   "Show usage statistics and metrics",
+				Flags:   UsageQueryFlags,
+				Action:  flags.Wrap(UsageSummaryCommand()),
+				Subcommands: []cli.Command{
+					{
+						Name:    "summary",
+						Aliases: []string{"us"},
+						Usage:   "Generate daily usage summary report",
+						Flags:   UsageQueryFlags,
+						Action:  flags.Wrap(UsageSummaryCommand()),
+					},
+					{
+						Name:    "metrics",
+						Aliases: []string{"um"},
+						Usage:   "Calculate and display productivity metrics",
+						Flags:   UsageQueryFlags,
+						Action:  flags.Wrap(UsageMetricsCommand()),
+					},
+					{
+						Name:    "patterns",
+						Aliases: []string{"up"},
+						Usage:   "Analyze usage patterns over time period",
+						Flags:   UsageQueryFlags,
+						Action:  flags.Wrap(UsagePatternsCommand()),
+					},
+					{
+						Name:        "benchmark",
+						Aliases:     []string{"ub"},
+						Usage:       "Compare current usage against baseline period",
+						Flags:       UsageQueryFlags,
+						Action:      flags.Wrap(UsageBenchmarkCommand()),
+						Subcommands: BenchmarkSubcommands,
+					},
+				},
+			},
+			{
+				Name:   "daemon",
+				Usage:  "Continuously collect usage metrics in the background",
+				Flags:  DaemonFlags,
+				Action: flags.Wrap(DaemonCommand()),
+			},
+			{
+				Name:  "metrics",
+				Usage: "Prometheus metrics for usage analytics",
+				Subcommands: []cli.Command{
+					{
+						Name:   "serve",
+						Usage:  "Expose /metrics over HTTP for scraping",
+						Flags:  metrics.Flags,
+						Action: flags.Wrap(metrics.ServeCommand(reg)),
+					},
+				},
+			},
+		},
+	}
+	flags.Init(app)
+	return app
+}
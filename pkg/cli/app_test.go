@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IniZio/vendatta-config/pkg/daemon/store"
+	"github.com/IniZio/vendatta-config/pkg/metrics"
+)
+
+// seedStore writes two aggregate windows so the usage commands under
+// test have something to summarize/analyze/benchmark.
+func seedStore(t *testing.T, path string) {
+	t.Helper()
+	st, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	if err := st.Append(store.Aggregate{Window: now.Add(-time.Hour), Metrics: map[string]float64{"commits": 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Append(store.Aggregate{Window: now, Metrics: map[string]float64{"commits": 3}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runApp runs NewApp with args (which must start with "usage") against
+// the BoltDB store at storePath (seeding it with sample aggregates
+// first if it doesn't exist yet) and a baseline store rooted at
+// XDG_STATE_HOME=baseDir, returning the run error and the JSON log
+// lines it produced. --store is inserted right after "usage", the first
+// command in the chain that declares it; placing it at the end of argv
+// would make deeper subcommands that don't re-declare it (such as
+// `benchmark save`) reject it as an unknown flag.
+func runApp(t *testing.T, baseDir, storePath string, args ...string) (error, string) {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", baseDir)
+
+	if _, err := os.Stat(storePath); os.IsNotExist(err) {
+		seedStore(t, storePath)
+	}
+
+	logFile := filepath.Join(t.TempDir(), "log.json")
+	app := NewApp(metrics.NewRegistry())
+
+	full := []string{"vendatta", "--verbose=0", "--log-format", "json", "--log-file", logFile}
+	full = append(full, args[0], "--store", storePath)
+	full = append(full, args[1:]...)
+	err := app.Run(full)
+
+	data, readErr := os.ReadFile(logFile)
+	if readErr != nil {
+		return err, ""
+	}
+	return err, string(data)
+}
+
+// TestUsageSubcommandsDispatchDistinctly guards against `usage
+// metrics`/`usage patterns`/`usage benchmark` silently falling through
+// to UsageSummaryCommand, which urfave/cli v1 does if those verbs are
+// registered as flat "usage metrics"-named top-level commands instead
+// of as Subcommands nested under "usage" (App.Run only matches
+// args.First() against a command name).
+func TestUsageSubcommandsDispatchDistinctly(t *testing.T) {
+	cases := []struct {
+		args    []string
+		wantLog string
+	}{
+		{[]string{"usage"}, `"msg":"usage summary"`},
+		{[]string{"usage", "summary"}, `"msg":"usage summary"`},
+		{[]string{"usage", "metrics"}, `"msg":"usage metric"`},
+		{[]string{"usage", "patterns"}, `"msg":"usage patterns"`},
+		{[]string{"usage", "benchmark"}, `"msg":"usage benchmark"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			dir := t.TempDir()
+			err, log := runApp(t, dir, filepath.Join(dir, "daemon.db"), tc.args...)
+			if err != nil {
+				t.Fatalf("app.Run(%v): %v", tc.args, err)
+			}
+			if !strings.Contains(log, tc.wantLog) {
+				t.Errorf("app.Run(%v): log = %q, want it to contain %q", tc.args, log, tc.wantLog)
+			}
+			if tc.wantLog != `"msg":"usage summary"` && strings.Contains(log, `"msg":"usage summary"`) {
+				t.Errorf("app.Run(%v): log unexpectedly contains \"usage summary\": %q", tc.args, log)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/IniZio/vendatta-config/pkg/metrics/baseline"
+	"github.com/urfave/cli"
+)
+
+// BenchmarkSubcommands are the `usage benchmark` verbs: save, list and
+// compare against a persisted baseline.
+var BenchmarkSubcommands = []cli.Command{
+	{
+		Name:      "save",
+		Usage:     "Save the current usage metrics as a named baseline",
+		ArgsUsage: "<name>",
+		Action:    benchmarkSaveCommand(),
+	},
+	{
+		Name:   "list",
+		Usage:  "List saved baselines",
+		Action: benchmarkListCommand(),
+	},
+	{
+		Name:      "compare",
+		Usage:     "Compare current usage against a saved baseline",
+		ArgsUsage: "<name>",
+		Flags: []cli.Flag{
+			cli.DurationFlag{Name: "since", Usage: "Only include events from this far back"},
+			cli.Float64Flag{Name: "alpha", Usage: "Significance threshold for the Welch's t-test", Value: 0.05},
+			cli.StringFlag{Name: "format", Usage: "Output format: text|json", Value: "text"},
+		},
+		Action: benchmarkCompareCommand(),
+	},
+}
+
+func benchmarkSaveCommand() cli.ActionFunc {
+	return func(c *cli.Context) error {
+		name := c.Args().First()
+		if name == "" {
+			return fmt.Errorf("usage benchmark save: a baseline name is required")
+		}
+		store, err := baseline.NewStore()
+		if err != nil {
+			return err
+		}
+		snapshot := baseline.Snapshot{
+			SavedAt: time.Now(),
+			Metrics: currentMetricSnapshot(c),
+		}
+		return store.Save(name, snapshot)
+	}
+}
+
+func benchmarkListCommand() cli.ActionFunc {
+	return func(c *cli.Context) error {
+		store, err := baseline.NewStore()
+		if err != nil {
+			return err
+		}
+		names, err := store.List()
+		if err != nil {
+			return err
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+}
+
+func benchmarkCompareCommand() cli.ActionFunc {
+	return func(c *cli.Context) error {
+		name := c.Args().First()
+		if name == "" {
+			return fmt.Errorf("usage benchmark compare: a baseline name is required")
+		}
+		store, err := baseline.NewStore()
+		if err != nil {
+			return err
+		}
+		saved, err := store.Load(name)
+		if err != nil {
+			return err
+		}
+		current := baseline.Snapshot{SavedAt: time.Now(), Metrics: currentMetricSnapshot(c)}
+
+		results := compareSnapshots(current, saved, c.Float64("alpha"))
+
+		if c.String("format") == "json" {
+			return json.NewEncoder(os.Stdout).Encode(results)
+		}
+		for _, r := range results {
+			fmt.Printf("%-24s t=%.3f df=%.1f p=%.4f significant=%v\n", r.Name, r.T, r.DF, r.PValue, r.Signif)
+		}
+		return nil
+	}
+}
+
+// comparison is one metric's Welch's t-test result against a baseline,
+// identified by name so --format=json output is self-describing.
+type comparison struct {
+	Name string `json:"name"`
+	baseline.WelchResult
+}
+
+// compareSnapshots runs a Welch's t-test for every metric present in
+// both snapshots with at least two observations on each side; other
+// metrics are left out of the result.
+func compareSnapshots(current, saved baseline.Snapshot, alpha float64) []comparison {
+	savedByName := make(map[string]baseline.Metric, len(saved.Metrics))
+	for _, m := range saved.Metrics {
+		savedByName[m.Name] = m
+	}
+
+	var out []comparison
+	for _, cur := range current.Metrics {
+		base, ok := savedByName[cur.Name]
+		if !ok || cur.N < 2 || base.N < 2 {
+			continue
+		}
+		result := baseline.WelchTTest(cur.Mean, cur.Variance, cur.N, base.Mean, base.Variance, base.N, alpha)
+		out = append(out, comparison{Name: cur.Name, WelchResult: result})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// currentMetricSnapshot computes mean/variance/n for each productivity
+// metric over the events in scope for c, suitable for saving as a
+// baseline or comparing against one.
+func currentMetricSnapshot(c *cli.Context) []baseline.Metric {
+	events := loadUsageEvents(c)
+	byCategory := make(map[string][]float64)
+	for _, e := range events {
+		byCategory[e.Category] = append(byCategory[e.Category], e.Value)
+	}
+
+	metrics := make([]baseline.Metric, 0, len(byCategory))
+	for name, values := range byCategory {
+		mean, variance := meanAndVariance(values)
+		metrics = append(metrics, baseline.Metric{Name: name, Mean: mean, Variance: variance, N: len(values)})
+	}
+	return metrics
+}
+
+// meanAndVariance returns the sample mean and unbiased sample variance
+// of values.
+func meanAndVariance(values []float64) (mean, variance float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean = sum / float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var sq float64
+	for _, v := range values {
+		d := v - mean
+		sq += d * d
+	}
+	variance = sq / float64(len(values)-1)
+	return mean, variance
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestUsageBenchmarkSubcommandsReachable guards against `usage
+// benchmark save/list/compare` being unreachable dead code: if
+// dispatch stopped at "usage benchmark" (see
+// TestUsageSubcommandsDispatchDistinctly in app_test.go), these verbs
+// would never run at all.
+func TestUsageBenchmarkSubcommandsReachable(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "daemon.db")
+
+	// "save" with no name argument only returns this error from
+	// benchmarkSaveCommand itself, proving dispatch reached the nested
+	// subcommand rather than falling through to UsageBenchmarkCommand
+	// (which doesn't require a name and wouldn't error here).
+	if err, _ := runApp(t, dir, storePath, "usage", "benchmark", "save"); err == nil || !strings.Contains(err.Error(), "a baseline name is required") {
+		t.Errorf("usage benchmark save (no name): err = %v, want baseline name error", err)
+	}
+
+	if err, _ := runApp(t, dir, storePath, "usage", "benchmark", "save", "baseline1"); err != nil {
+		t.Fatalf("usage benchmark save baseline1: %v", err)
+	}
+
+	if err, _ := runApp(t, dir, storePath, "usage", "benchmark", "list"); err != nil {
+		t.Errorf("usage benchmark list: %v", err)
+	}
+
+	if err, _ := runApp(t, dir, storePath, "usage", "benchmark", "compare"); err == nil || !strings.Contains(err.Error(), "a baseline name is required") {
+		t.Errorf("usage benchmark compare (no name): err = %v, want baseline name error", err)
+	}
+}
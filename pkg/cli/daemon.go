@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/IniZio/vendatta-config/pkg/daemon"
+	"github.com/urfave/cli"
+)
+
+// DaemonFlags are the flags accepted by `vendatta daemon`.
+var DaemonFlags = []cli.Flag{
+	cli.StringFlag{Name: "pidfile", Usage: "Path to write the daemon's PID to"},
+	cli.StringFlag{Name: "store", Usage: "Path to the BoltDB aggregate store", Value: defaultStorePath()},
+	cli.BoolFlag{Name: "foreground", Usage: "Run the daemon in the foreground (default)"},
+	cli.BoolFlag{Name: "detach", Usage: "Fork the daemon into the background and exit"},
+	cli.DurationFlag{Name: "roll-interval", Usage: "How often raw events are rolled into a persisted aggregate", Value: time.Minute},
+}
+
+// DaemonCommand runs the supervised sampler/processor loop that backs
+// historical usage queries, shutting down cleanly on SIGINT/SIGTERM.
+func DaemonCommand() cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if c.Bool("detach") {
+			return detach(c)
+		}
+
+		d, err := daemon.New(daemon.Options{
+			StorePath:    c.String("store"),
+			PIDFile:      c.String("pidfile"),
+			RollInterval: c.Duration("roll-interval"),
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		return d.Run(ctx)
+	}
+}
+
+// detach re-execs the current binary in the foreground, detached from
+// the controlling terminal, and returns immediately so the parent can
+// exit. This is the --detach half of the --foreground/--detach split.
+//
+// The child's argv is rebuilt from the flags DaemonCommand actually
+// parsed, not copied from os.Args: the raw tail still contains
+// --detach, and re-using it would have the child re-enter this same
+// branch and fork again, forever.
+func detach(c *cli.Context) error {
+	args := []string{"daemon", "--foreground"}
+	if v := c.String("pidfile"); v != "" {
+		args = append(args, "--pidfile", v)
+	}
+	if v := c.String("store"); v != "" {
+		args = append(args, "--store", v)
+	}
+	if v := c.Duration("roll-interval"); v > 0 {
+		args = append(args, "--roll-interval", v.String())
+	}
+
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Stdin = nil
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	return cmd.Start()
+}
+
+// defaultStorePath returns $XDG_STATE_HOME/vendatta/daemon.db, falling
+// back to ~/.local/state when XDG_STATE_HOME is unset.
+func defaultStorePath() string {
+	base := os.Getenv("XDG_STATE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		base = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(base, "vendatta", "daemon.db")
+}
@@ -0,0 +1,218 @@
+// Package cli implements the vendatta command-line actions.
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/IniZio/vendatta-config/pkg/daemon"
+	"github.com/IniZio/vendatta-config/pkg/daemon/store"
+	"github.com/IniZio/vendatta-config/pkg/log"
+	"github.com/IniZio/vendatta-config/pkg/metrics"
+	"github.com/urfave/cli"
+)
+
+// UsageQueryFlags are the flags shared by every usage subcommand for
+// selecting which recorded window to report on.
+var UsageQueryFlags = []cli.Flag{
+	cli.DurationFlag{Name: "since", Usage: "Only include events from this far back (default 24h)"},
+	cli.StringFlag{Name: "store", Usage: "Path to the daemon's BoltDB aggregate store", Value: defaultStorePath()},
+}
+
+// UsageEvent is a single recorded productivity event. It is an alias of
+// daemon.UsageEvent, which is where the type actually lives so that
+// pkg/daemon (needed here for DaemonCommand) doesn't have to import
+// pkg/cli back.
+type UsageEvent = daemon.UsageEvent
+
+// UsageSummaryCommand renders a one-shot daily summary of recorded usage
+// events, and records one events_total observation per event so
+// `metrics serve`/--pushgateway reflect real activity.
+func UsageSummaryCommand() cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := loggerContext(c)
+		events := loadUsageEvents(c)
+
+		reg := registryFromContext(c)
+		for _, e := range events {
+			reg.ObserveEvent(e.Category)
+		}
+
+		log.FromContext(ctx).Info("usage summary", "events", len(events))
+		return pushIfConfigured(c, reg)
+	}
+}
+
+// UsageMetricsCommand calculates per-category totals from recorded usage
+// events, feeding each one into the shared metrics.Registry.
+func UsageMetricsCommand() cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := loggerContext(c)
+		logger := log.FromContext(ctx)
+		events := loadUsageEvents(c)
+
+		reg := registryFromContext(c)
+		for name, value := range aggregateMetrics(events) {
+			reg.SetMetric(name, value)
+			logger.Info("usage metric", "name", name, "value", value)
+		}
+		return pushIfConfigured(c, reg)
+	}
+}
+
+// UsagePatternsCommand analyzes usage events over the requested time
+// period, reporting the gaps between consecutive events as pattern
+// duration samples.
+func UsagePatternsCommand() cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := loggerContext(c)
+		logger := log.FromContext(ctx)
+		events := loadUsageEvents(c)
+
+		reg := registryFromContext(c)
+		for _, gap := range eventGaps(events) {
+			reg.ObservePatternDuration("interval", gap.Seconds())
+		}
+
+		logger.Info("usage patterns", "events", len(events))
+		return pushIfConfigured(c, reg)
+	}
+}
+
+// UsageBenchmarkCommand reports the current period's per-category
+// totals, feeding them into the shared metrics.Registry the same way
+// UsageMetricsCommand does. It does not compare them against a
+// baseline; the `usage benchmark compare` subcommand does that.
+func UsageBenchmarkCommand() cli.ActionFunc {
+	return func(c *cli.Context) error {
+		ctx := loggerContext(c)
+		logger := log.FromContext(ctx)
+		events := loadUsageEvents(c)
+
+		reg := registryFromContext(c)
+		current := aggregateMetrics(events)
+		for name, value := range current {
+			reg.SetMetric(name, value)
+			logger.Info("usage benchmark", "name", name, "value", value)
+		}
+		return pushIfConfigured(c, reg)
+	}
+}
+
+// loggerContext builds a context.Context carrying the *slog.Logger
+// stashed on the app's Metadata by the app's Before hook (see before in
+// app.go), so commands log through it
+// instead of a package-global logger.
+func loggerContext(c *cli.Context) context.Context {
+	ctx := context.Background()
+	if logger, ok := c.App.Metadata["logger"].(*slog.Logger); ok {
+		return log.WithContext(ctx, logger)
+	}
+	return ctx
+}
+
+// registryFromContext returns the *metrics.Registry the app's Before hook
+// stashed on the app's Metadata, so every command feeds the same collectors that
+// `metrics serve` and the InfluxDB reporter read from. Falling back to a
+// throwaway registry keeps commands working (e.g. in tests) when no app
+// wired one in, at the cost of that one invocation's samples going
+// nowhere.
+func registryFromContext(c *cli.Context) *metrics.Registry {
+	if reg, ok := c.App.Metadata["metrics"].(*metrics.Registry); ok {
+		return reg
+	}
+	return metrics.NewRegistry()
+}
+
+// pushIfConfigured pushes reg's current samples to the Pushgateway URL
+// named by --pushgateway, if the flag was set, so short-lived CLI runs
+// get their metrics into Prometheus before exiting.
+func pushIfConfigured(c *cli.Context, reg *metrics.Registry) error {
+	gateway := c.GlobalString("pushgateway")
+	if gateway == "" {
+		return nil
+	}
+	return metrics.PushSnapshot(gateway, "vendatta_"+c.Command.Name, reg)
+}
+
+// eventGaps returns the time elapsed between each pair of consecutive
+// events, in timestamp order.
+func eventGaps(events []UsageEvent) []time.Duration {
+	sorted := append([]UsageEvent(nil), events...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	gaps := make([]time.Duration, 0, len(sorted))
+	for i := 1; i < len(sorted); i++ {
+		gaps = append(gaps, sorted[i].Timestamp.Sub(sorted[i-1].Timestamp))
+	}
+	return gaps
+}
+
+// loadUsageEvents reads the recorded usage events for the window implied
+// by the command's flags. There is no raw-event source (nothing sends
+// on the daemon's events channel), so this synthesizes one "historical"
+// event per metric per persisted aggregate window in range, if a store
+// exists at the selected path.
+func loadUsageEvents(c *cli.Context) []UsageEvent {
+	path := daemonStorePath(c)
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+
+	st, err := store.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer st.Close()
+
+	aggregates, err := st.Since(time.Now().Add(-sinceFlag(c)))
+	if err != nil {
+		return nil
+	}
+
+	var events []UsageEvent
+	for _, a := range aggregates {
+		for category, value := range a.Metrics {
+			events = append(events, UsageEvent{Timestamp: a.Window, Category: category, Value: value})
+		}
+	}
+	return events
+}
+
+// sinceFlag returns the --since window, defaulting to 24h when unset so
+// a bare `usage summary` still reports something.
+func sinceFlag(c *cli.Context) time.Duration {
+	if d := c.Duration("since"); d > 0 {
+		return d
+	}
+	if d := c.GlobalDuration("since"); d > 0 {
+		return d
+	}
+	return 24 * time.Hour
+}
+
+// daemonStorePath mirrors defaultStorePath in daemon.go so usage
+// commands read the same BoltDB file the daemon writes to, unless
+// overridden with --store.
+func daemonStorePath(c *cli.Context) string {
+	if p := c.String("store"); p != "" {
+		return p
+	}
+	if p := c.GlobalString("store"); p != "" {
+		return p
+	}
+	return defaultStorePath()
+}
+
+// aggregateMetrics rolls a slice of usage events up into named
+// productivity metrics.
+func aggregateMetrics(events []UsageEvent) map[string]float64 {
+	totals := make(map[string]float64)
+	for _, e := range events {
+		totals[e.Category] += e.Value
+	}
+	return totals
+}
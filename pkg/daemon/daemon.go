@@ -0,0 +1,137 @@
+// Package daemon runs the long-lived supervised loop backing
+// `vendatta daemon`: a sampler goroutine owns the raw usage event
+// channel, a processor goroutine rolls the events it receives into the
+// same aggregates the usage commands compute on demand, and each
+// aggregate window is persisted so historical windows can be queried
+// without rescanning raw events. No event source feeds the sampler, so
+// it produces no events.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/IniZio/vendatta-config/pkg/daemon/store"
+)
+
+// Options configures a single daemon run.
+type Options struct {
+	// StorePath is the BoltDB file aggregates are persisted to.
+	StorePath string
+	// PIDFile, if set, is written with the daemon's PID on start and
+	// removed on clean shutdown, so it can be managed by systemd.
+	PIDFile string
+	// RollInterval is how often the processor rolls raw events into a
+	// persisted aggregate window.
+	RollInterval time.Duration
+}
+
+// Daemon supervises the sampler and processor goroutines.
+type Daemon struct {
+	opts Options
+	st   *store.Store
+
+	events chan UsageEvent
+}
+
+// New opens the backing store and prepares a Daemon. Call Run to start
+// the sampler/processor loop.
+func New(opts Options) (*Daemon, error) {
+	if opts.RollInterval <= 0 {
+		opts.RollInterval = time.Minute
+	}
+	st, err := store.Open(opts.StorePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Daemon{opts: opts, st: st, events: make(chan UsageEvent, 256)}, nil
+}
+
+// Run writes the pidfile (if configured) and blocks running the
+// sampler and processor until ctx is canceled, then shuts down cleanly.
+func (d *Daemon) Run(ctx context.Context) error {
+	if d.opts.PIDFile != "" {
+		if err := writePIDFile(d.opts.PIDFile); err != nil {
+			return err
+		}
+		defer os.Remove(d.opts.PIDFile)
+	}
+	defer d.st.Close()
+
+	done := make(chan error, 2)
+	go func() { done <- d.sample(ctx) }()
+	go func() { done <- d.process(ctx) }()
+
+	var firstErr error
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// sample waits until ctx is canceled and then closes the events
+// channel. It gathers no events itself and nothing else sends on
+// d.events, so process/roll never has anything to persist; the
+// store.Since-backed read path in pkg/cli.loadUsageEvents only sees
+// aggregates written by other means.
+func (d *Daemon) sample(ctx context.Context) error {
+	<-ctx.Done()
+	close(d.events)
+	return nil
+}
+
+// process rolls buffered raw events into aggregates on RollInterval and
+// persists each window, until the events channel is closed or ctx is
+// canceled; either way it persists what it has buffered and returns,
+// without reading events still queued in the channel.
+func (d *Daemon) process(ctx context.Context) error {
+	ticker := time.NewTicker(d.opts.RollInterval)
+	defer ticker.Stop()
+
+	var buffered []UsageEvent
+	for {
+		select {
+		case e, ok := <-d.events:
+			if !ok {
+				return d.roll(buffered)
+			}
+			buffered = append(buffered, e)
+		case <-ticker.C:
+			if err := d.roll(buffered); err != nil {
+				return err
+			}
+			buffered = nil
+		case <-ctx.Done():
+			return d.roll(buffered)
+		}
+	}
+}
+
+// roll persists one aggregate window from the buffered raw events.
+func (d *Daemon) roll(events []UsageEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	totals := make(map[string]float64)
+	for _, e := range events {
+		totals[e.Category] += e.Value
+	}
+	return d.st.Append(store.Aggregate{Window: time.Now(), Metrics: totals})
+}
+
+// writePIDFile records the current process PID at path, failing if the
+// file already exists so two daemons can't stomp on each other.
+func writePIDFile(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("daemon: pidfile %s: %w", path, err)
+	}
+	defer f.Close()
+	_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+	return err
+}
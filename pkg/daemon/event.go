@@ -0,0 +1,13 @@
+package daemon
+
+import "time"
+
+// UsageEvent is a single recorded productivity event (e.g. a commit, a
+// completed task, a focus-session tick). It lives in pkg/daemon (rather
+// than pkg/cli, which needs to depend on this package for Daemon/Options)
+// so the two packages don't form an import cycle.
+type UsageEvent struct {
+	Timestamp time.Time
+	Category  string
+	Value     float64
+}
@@ -0,0 +1,82 @@
+// Package store persists rolling usage aggregates so usage summary and
+// usage benchmark can answer against historical windows without
+// rescanning raw events.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketAggregates = []byte("aggregates")
+
+// Aggregate is one rolled-up window of usage metrics, keyed by the time
+// the window closed.
+type Aggregate struct {
+	Window  time.Time          `json:"window"`
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// Store is a BoltDB-backed append log of rolling aggregates.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens (creating if necessary) the BoltDB file at path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("daemon/store: open %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketAggregates)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (s *Store) Close() error { return s.db.Close() }
+
+// Append persists one rolled-up aggregate window.
+func (s *Store) Append(a Aggregate) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketAggregates)
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		key, err := a.Window.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return b.Put(key, data)
+	})
+}
+
+// Since returns every aggregate window that closed at or after t, in
+// chronological order.
+func (s *Store) Since(t time.Time) ([]Aggregate, error) {
+	var out []Aggregate
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketAggregates)
+		return b.ForEach(func(_, v []byte) error {
+			var a Aggregate
+			if err := json.Unmarshal(v, &a); err != nil {
+				return err
+			}
+			if !a.Window.Before(t) {
+				out = append(out, a)
+			}
+			return nil
+		})
+	})
+	return out, err
+}
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"strconv"
+
+	"github.com/urfave/cli"
+)
+
+// verbosityValue implements flag.Value (and flag's IsBoolFlag escape
+// hatch) so that a bare `--verbose` means LevelInfo while `--verbose=3`
+// still parses the explicit integer level, mirroring the NoOptDefVal
+// pattern pflag-based tools use for the same flag.
+type verbosityValue struct {
+	level *Level
+}
+
+func (v *verbosityValue) String() string {
+	if v.level == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*v.level))
+}
+
+func (v *verbosityValue) Set(s string) error {
+	if s == "true" {
+		*v.level = LevelInfo
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	*v.level = Level(n)
+	return nil
+}
+
+// IsBoolFlag makes the stdlib flag package accept a bare `--verbose`
+// with no `=value`, calling Set("true") instead of requiring an arg.
+func (v *verbosityValue) IsBoolFlag() bool { return true }
+
+var verbosity = LevelWarn
+
+// Flags are the global logging flags: --verbose, --log-format and
+// --log-file.
+var Flags = []cli.Flag{
+	cli.GenericFlag{
+		Name:  "verbose",
+		Usage: "Log verbosity: 0=all 1=info 2=warn 3=error 4=fail (bare --verbose means 1)",
+		Value: &verbosityValue{level: &verbosity},
+	},
+	cli.StringFlag{
+		Name:  "log-format",
+		Usage: "Log output format: text|json",
+		Value: "text",
+	},
+	cli.StringFlag{
+		Name:  "log-file",
+		Usage: "Write logs to this file instead of stderr",
+	},
+}
+
+// ConfigFromContext builds a Config from the --verbose/--log-format/
+// --log-file global flags.
+func ConfigFromContext(c *cli.Context) Config {
+	return Config{
+		Verbosity: verbosity,
+		Format:    c.GlobalString("log-format"),
+		File:      c.GlobalString("log-file"),
+	}
+}
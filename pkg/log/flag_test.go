@@ -0,0 +1,33 @@
+package log
+
+import "testing"
+
+func TestVerbosityValueBareFlagMeansInfo(t *testing.T) {
+	level := LevelWarn
+	v := &verbosityValue{level: &level}
+
+	if err := v.Set("true"); err != nil {
+		t.Fatalf("Set(true): %v", err)
+	}
+	if level != LevelInfo {
+		t.Errorf("bare --verbose: got level %d, want %d", level, LevelInfo)
+	}
+}
+
+func TestVerbosityValueExplicitLevel(t *testing.T) {
+	level := LevelWarn
+	v := &verbosityValue{level: &level}
+
+	if err := v.Set("3"); err != nil {
+		t.Fatalf("Set(3): %v", err)
+	}
+	if level != LevelError {
+		t.Errorf("--verbose=3: got level %d, want %d", level, LevelError)
+	}
+}
+
+func TestLevelFailAboveError(t *testing.T) {
+	if LevelFail.slogLevel() <= LevelError.slogLevel() {
+		t.Errorf("LevelFail.slogLevel() = %v, want greater than LevelError.slogLevel() = %v", LevelFail.slogLevel(), LevelError.slogLevel())
+	}
+}
@@ -0,0 +1,95 @@
+// Package log wraps log/slog with the integer-verbosity CLI convention
+// used elsewhere in the ecosystem (0=all, 1=info, 2=warn, 3=error,
+// 4=fail), plus text/json output and an optional log file, so the
+// daemon can ship structured logs to a log-shipper.
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Level is a verbosity threshold: records below the configured Level
+// are discarded. Higher values mean "show less".
+type Level int
+
+const (
+	LevelAll   Level = 0 // show everything, including debug records
+	LevelInfo  Level = 1
+	LevelWarn  Level = 2
+	LevelError Level = 3
+	LevelFail  Level = 4 // show only fatal records
+)
+
+// levelFail sits above slog.LevelError so --verbose=4 suppresses even
+// error records and only fatal ("fail") records are emitted.
+const levelFail slog.Level = slog.LevelError + 4
+
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case LevelAll:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	case LevelFail:
+		return levelFail
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Config controls how New builds a logger.
+type Config struct {
+	Verbosity Level
+	// Format is "text" or "json"; anything else falls back to "text".
+	Format string
+	// File, if set, is opened for append and used instead of stderr.
+	File string
+}
+
+// New builds a *slog.Logger honoring cfg. Callers are responsible for
+// closing cfg.File's underlying handle via the process exiting, as
+// there is no Close on the returned logger.
+func New(cfg Config) (*slog.Logger, error) {
+	var w io.Writer = os.Stderr
+	if cfg.File != "" {
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("log: open %s: %w", cfg.File, err)
+		}
+		w = f
+	}
+
+	opts := &slog.HandlerOptions{Level: cfg.Verbosity.slogLevel()}
+	var handler slog.Handler
+	if cfg.Format == "json" {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	return slog.New(handler), nil
+}
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying logger, retrievable with
+// FromContext.
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext returns the logger stashed by WithContext, or
+// slog.Default() if none was stashed.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
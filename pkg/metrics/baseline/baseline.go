@@ -0,0 +1,110 @@
+// Package baseline persists named snapshots of usage metrics so
+// `usage benchmark` can compare the current period against a saved
+// baseline instead of an unspecified one.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Metric is one named measurement's summary statistics, sufficient to
+// run a Welch's t-test against another snapshot of the same metric.
+type Metric struct {
+	Name     string  `json:"name"`
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	N        int     `json:"n"`
+}
+
+// Snapshot is a named, timestamped set of metric summaries.
+type Snapshot struct {
+	Name    string    `json:"name"`
+	SavedAt time.Time `json:"saved_at"`
+	Metrics []Metric  `json:"metrics"`
+}
+
+// Store persists Snapshots as one JSON file per name under baseDir.
+type Store struct {
+	dir string
+}
+
+// NewStore opens a Store rooted at $XDG_STATE_HOME/vendatta/baselines,
+// falling back to ~/.local/state when XDG_STATE_HOME is unset.
+func NewStore() (*Store, error) {
+	base := os.Getenv("XDG_STATE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		base = filepath.Join(home, ".local", "state")
+	}
+	dir := filepath.Join(base, "vendatta", "baselines")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("baseline: create %s: %w", dir, err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Save persists snapshot under name, overwriting any existing baseline
+// with that name.
+func (s *Store) Save(name string, snapshot Snapshot) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	snapshot.Name = name
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Load reads back the snapshot saved under name.
+func (s *Store) Load(name string) (Snapshot, error) {
+	var snapshot Snapshot
+	path, err := s.path(name)
+	if err != nil {
+		return snapshot, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, fmt.Errorf("baseline: load %q: %w", name, err)
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("baseline: parse %q: %w", name, err)
+	}
+	return snapshot, nil
+}
+
+// List returns the names of every saved baseline, in the order of their
+// file names (os.ReadDir sorts entries by file name), not by SavedAt.
+func (s *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+	return names, nil
+}
+
+// path resolves name to its on-disk location, rejecting any name that
+// isn't a single plain path element (e.g. "../../etc/passwd" or an
+// absolute path) so a name taken from user input can't escape s.dir.
+func (s *Store) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("baseline: invalid name %q", name)
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
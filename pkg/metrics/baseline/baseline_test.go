@@ -0,0 +1,58 @@
+package baseline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreSaveLoadList(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := Snapshot{
+		SavedAt: time.Now(),
+		Metrics: []Metric{{Name: "commits", Mean: 4.2, Variance: 1.1, N: 14}},
+	}
+	if err := store.Save("pre-refactor", snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := store.Load("pre-refactor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Metrics) != 1 || loaded.Metrics[0].Name != "commits" {
+		t.Errorf("unexpected loaded snapshot: %+v", loaded)
+	}
+
+	names, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "pre-refactor" {
+		t.Errorf("unexpected baseline list: %v", names)
+	}
+}
+
+func TestStoreRejectsPathTraversal(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := Snapshot{SavedAt: time.Now(), Metrics: []Metric{{Name: "commits", Mean: 1, Variance: 0, N: 1}}}
+	for _, name := range []string{"../escape", "a/b", "/etc/passwd", ".", ".."} {
+		if err := store.Save(name, snapshot); err == nil {
+			t.Errorf("Save(%q): expected error, got nil", name)
+		}
+		if _, err := store.Load(name); err == nil {
+			t.Errorf("Load(%q): expected error, got nil", name)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package baseline
+
+import "math"
+
+// WelchResult is the result of a two-sample Welch's t-test on one
+// metric.
+type WelchResult struct {
+	T      float64
+	DF     float64
+	PValue float64
+	Signif bool
+}
+
+// WelchTTest runs Welch's t-test comparing two independent samples
+// summarized by their mean, variance and count, and reports whether the
+// two-tailed p-value is below alpha.
+func WelchTTest(mean1, var1 float64, n1 int, mean2, var2 float64, n2 int, alpha float64) WelchResult {
+	se1 := var1 / float64(n1)
+	se2 := var2 / float64(n2)
+	se := se1 + se2
+
+	t := (mean1 - mean2) / math.Sqrt(se)
+
+	// Welch-Satterthwaite degrees of freedom.
+	df := (se * se) / (se1*se1/float64(n1-1) + se2*se2/float64(n2-1))
+
+	p := twoTailedPValue(t, df)
+	return WelchResult{T: t, DF: df, PValue: p, Signif: p < alpha}
+}
+
+// twoTailedPValue computes P(|T| > |t|) for a Student's t distribution
+// with df degrees of freedom, via the regularized incomplete beta
+// function: CDF(t) = 1 - 0.5*I_x(df/2, 1/2) where x = df/(df+t^2).
+func twoTailedPValue(t, df float64) float64 {
+	x := df / (df + t*t)
+	return regularizedIncompleteBeta(x, df/2, 0.5)
+}
+
+// regularizedIncompleteBeta computes I_x(a, b) using the continued
+// fraction expansion from Numerical Recipes, which converges quickly
+// for the a,b ranges a t-test produces.
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lbeta := lgammaSum(a, b)
+	front := math.Exp(lbeta + a*math.Log(x) + b*math.Log(1-x))
+
+	if x < (a+1)/(a+b+2) {
+		return front * betacf(x, a, b) / a
+	}
+	return 1 - front*betacf(1-x, b, a)/b
+}
+
+// lgammaSum returns log(Gamma(a+b)/(Gamma(a)*Gamma(b))), i.e. log(1/B(a,b)).
+func lgammaSum(a, b float64) float64 {
+	lgA, _ := math.Lgamma(a)
+	lgB, _ := math.Lgamma(b)
+	lgAB, _ := math.Lgamma(a + b)
+	return lgAB - lgA - lgB
+}
+
+// betacf evaluates the continued fraction for the incomplete beta
+// function, as in Numerical Recipes' betacf.
+func betacf(x, a, b float64) float64 {
+	const maxIter = 200
+	const epsilon = 3e-12
+	const tiny = 1e-30
+
+	qab := a + b
+	qap := a + 1
+	qam := a - 1
+
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,27 @@
+package baseline
+
+import "testing"
+
+func TestWelchTTestIdenticalSamplesNotSignificant(t *testing.T) {
+	result := WelchTTest(10, 2, 30, 10, 2, 30, 0.05)
+	if result.Signif {
+		t.Errorf("identical samples flagged significant: %+v", result)
+	}
+}
+
+func TestWelchTTestLargeShiftIsSignificant(t *testing.T) {
+	result := WelchTTest(20, 1, 30, 10, 1, 30, 0.05)
+	if !result.Signif {
+		t.Errorf("large mean shift not flagged significant: %+v", result)
+	}
+	if result.PValue >= 0.05 {
+		t.Errorf("expected p-value below alpha, got %v", result.PValue)
+	}
+}
+
+func TestWelchTTestSmallShiftNotSignificant(t *testing.T) {
+	result := WelchTTest(10.05, 4, 20, 10, 4, 20, 0.05)
+	if result.Signif {
+		t.Errorf("tiny mean shift with high variance flagged significant: %+v", result)
+	}
+}
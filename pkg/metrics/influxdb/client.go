@@ -0,0 +1,79 @@
+package influxdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// httpClient is a minimal line-protocol writer for InfluxDB v1 and v2
+// HTTP write endpoints. It intentionally avoids pulling in the full
+// influxdb-client-go SDK for a handful of point writes.
+type httpClient struct {
+	cfg Config
+	hc  *http.Client
+}
+
+func newHTTPClient(cfg Config) (*httpClient, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("influxdb: endpoint is required")
+	}
+	return &httpClient{cfg: cfg, hc: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// WritePoint writes a single line-protocol point to the configured
+// InfluxDB endpoint, using the v2 write API when Bucket is set and the
+// v1 write API otherwise.
+func (w *httpClient) WritePoint(ctx context.Context, measurement string, tags map[string]string, fields map[string]interface{}, ts time.Time) error {
+	line := encodeLine(measurement, tags, fields, ts)
+
+	var url string
+	if w.cfg.V2() {
+		url = fmt.Sprintf("%s/api/v2/write?org=%s&bucket=%s&precision=ns", w.cfg.Endpoint, w.cfg.Org, w.cfg.Bucket)
+	} else {
+		url = fmt.Sprintf("%s/write?db=%s&precision=ns", w.cfg.Endpoint, w.cfg.Database)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(line))
+	if err != nil {
+		return err
+	}
+	if w.cfg.V2() {
+		req.Header.Set("Authorization", "Token "+w.cfg.Token)
+	} else if w.cfg.Username != "" {
+		req.SetBasicAuth(w.cfg.Username, w.cfg.Password)
+	}
+
+	resp, err := w.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("influxdb: write returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// encodeLine renders a single InfluxDB line-protocol point.
+func encodeLine(measurement string, tags map[string]string, fields map[string]interface{}, ts time.Time) string {
+	var b strings.Builder
+	b.WriteString(measurement)
+	for k, v := range tags {
+		fmt.Fprintf(&b, ",%s=%s", k, v)
+	}
+	b.WriteByte(' ')
+	first := true
+	for k, v := range fields {
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		fmt.Fprintf(&b, "%s=%v", k, v)
+	}
+	fmt.Fprintf(&b, " %d\n", ts.UnixNano())
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/IniZio/vendatta-config/pkg/metrics"
+	"github.com/urfave/cli"
+)
+
+// Flags are the global InfluxDB flags any subcommand can opt into.
+var Flags = []cli.Flag{
+	cli.StringFlag{Name: "influxdb.endpoint", Usage: "InfluxDB API endpoint"},
+	cli.StringFlag{Name: "influxdb.database", Usage: "InfluxDB v1 database name"},
+	cli.StringFlag{Name: "influxdb.bucket", Usage: "InfluxDB v2 bucket name"},
+	cli.StringFlag{Name: "influxdb.org", Usage: "InfluxDB v2 organization"},
+	cli.StringFlag{Name: "influxdb.token", Usage: "InfluxDB v2 auth token"},
+	cli.StringFlag{Name: "influxdb.username", Usage: "InfluxDB v1 username"},
+	cli.StringFlag{Name: "influxdb.password", Usage: "InfluxDB v1 password"},
+	cli.StringFlag{Name: "influxdb.tags", Usage: "Comma-separated key=value tags applied to every point"},
+	cli.DurationFlag{Name: "influxdb.interval", Usage: "Interval between InfluxDB flushes", Value: 10 * time.Second},
+}
+
+// lifecycle holds the reporter goroutine state threaded between Before
+// and After, since urfave/cli v1 has no per-run context to stash it in.
+var lifecycle struct {
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// Before starts the background reporter goroutine if --influxdb.endpoint
+// was supplied. It is registered as app.Before so every subcommand can
+// opt in just by declaring the Flags above.
+func Before(reg *metrics.Registry) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		endpoint := c.GlobalString("influxdb.endpoint")
+		if endpoint == "" {
+			return nil
+		}
+		tags, err := ParseTags(c.GlobalString("influxdb.tags"))
+		if err != nil {
+			return err
+		}
+		cfg := Config{
+			Endpoint: endpoint,
+			Database: c.GlobalString("influxdb.database"),
+			Bucket:   c.GlobalString("influxdb.bucket"),
+			Org:      c.GlobalString("influxdb.org"),
+			Token:    c.GlobalString("influxdb.token"),
+			Username: c.GlobalString("influxdb.username"),
+			Password: c.GlobalString("influxdb.password"),
+			Tags:     tags,
+			Interval: c.GlobalDuration("influxdb.interval"),
+		}
+		client, err := newHTTPClient(cfg)
+		if err != nil {
+			return err
+		}
+		reporter := NewReporter(cfg, reg, client)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		lifecycle.cancel = cancel
+		lifecycle.done = make(chan error, 1)
+		go func() { lifecycle.done <- reporter.Run(ctx) }()
+		return nil
+	}
+}
+
+// After cancels the reporter goroutine started by Before, if any, and
+// waits for its final flush to complete. It is registered as app.After.
+func After(c *cli.Context) error {
+	if lifecycle.cancel == nil {
+		return nil
+	}
+	lifecycle.cancel()
+	return <-lifecycle.done
+}
@@ -0,0 +1,123 @@
+// Package influxdb periodically writes usage metric samples to an
+// InfluxDB v1 or v2 endpoint, mirroring the way geth exports node
+// metrics for multi-machine comparisons in Grafana.
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IniZio/vendatta-config/pkg/metrics"
+)
+
+// Config holds the connection details for an InfluxDB v1 or v2 endpoint.
+// Database is used for v1 (alongside Username/Password); Bucket and Org
+// are used for v2 (alongside Token). Tags are applied to every point in
+// addition to the automatic "host" tag.
+type Config struct {
+	Endpoint string
+	Database string
+	Bucket   string
+	Org      string
+	Token    string
+	Username string
+	Password string
+	Tags     map[string]string
+
+	Interval time.Duration
+}
+
+// V2 reports whether the config targets an InfluxDB v2 endpoint (bucket
+// + org + token) rather than a v1 endpoint (database + username/password).
+func (c Config) V2() bool { return c.Bucket != "" }
+
+// Reporter periodically samples a metrics.Registry and writes each
+// sample as a point to InfluxDB until its context is canceled.
+type Reporter struct {
+	cfg    Config
+	reg    *metrics.Registry
+	client pointWriter
+}
+
+// pointWriter is the minimal surface this package needs from an InfluxDB
+// client, so tests can substitute a fake instead of a live server.
+type pointWriter interface {
+	WritePoint(ctx context.Context, measurement string, tags map[string]string, fields map[string]interface{}, ts time.Time) error
+}
+
+// NewReporter builds a Reporter for reg using cfg. The reporter is not
+// started until Run is called.
+func NewReporter(cfg Config, reg *metrics.Registry, client pointWriter) *Reporter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	return &Reporter{cfg: cfg, reg: reg, client: client}
+}
+
+// Run flushes samples at cfg.Interval until ctx is canceled, at which
+// point it performs one final flush and returns.
+func (r *Reporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.flush(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return r.flush(context.Background())
+		}
+	}
+}
+
+// flush writes one point per metric gauge value in the registry, as set
+// by UsageMetricsCommand and UsageBenchmarkCommand. The pattern-duration
+// histogram that UsagePatternsCommand feeds is not written.
+func (r *Reporter) flush(ctx context.Context) error {
+	tags, err := r.tags()
+	if err != nil {
+		return err
+	}
+	for name, value := range r.reg.Snapshot() {
+		fields := map[string]interface{}{"value": value}
+		if err := r.client.WritePoint(ctx, "vendatta_usage", tags, fields, time.Now()); err != nil {
+			return fmt.Errorf("influxdb: write point %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// tags returns the host + user-supplied tags applied to every point.
+func (r *Reporter) tags() (map[string]string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	tags := map[string]string{"host": hostname}
+	for k, v := range r.cfg.Tags {
+		tags[k] = v
+	}
+	return tags, nil
+}
+
+// ParseTags parses a comma-separated "key=value,key2=value2" string, as
+// accepted by the --influxdb.tags flag.
+func ParseTags(s string) (map[string]string, error) {
+	tags := make(map[string]string)
+	if s == "" {
+		return tags, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("influxdb: invalid tag %q, want key=value", pair)
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return tags, nil
+}
@@ -0,0 +1,116 @@
+// Package metrics exposes the productivity metrics produced by the usage
+// subcommands as Prometheus collectors, so they can be scraped directly
+// or pushed to a Pushgateway from short-lived CLI runs.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Sample is a single named measurement taken from the usage aggregates
+// (a count, a duration, a rate) at the time it was produced.
+type Sample struct {
+	Name  string
+	Value float64
+}
+
+// Registry owns the Prometheus collectors backing the usage metrics and
+// the registry they are registered against, so callers can either serve
+// them over HTTP or push them to a Pushgateway.
+type Registry struct {
+	reg *prometheus.Registry
+
+	eventsTotal   *prometheus.CounterVec
+	activeGauge   *prometheus.GaugeVec
+	durationHisto *prometheus.HistogramVec
+}
+
+// NewRegistry builds a Registry with the counter/gauge/histogram
+// collectors used by UsageMetricsCommand, UsagePatternsCommand and
+// UsageBenchmarkCommand, registered against a fresh prometheus.Registry.
+func NewRegistry() *Registry {
+	r := &Registry{
+		reg: prometheus.NewRegistry(),
+		eventsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "vendatta",
+			Subsystem: "usage",
+			Name:      "events_total",
+			Help:      "Total number of usage events recorded, by category.",
+		}, []string{"category"}),
+		activeGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "vendatta",
+			Subsystem: "usage",
+			Name:      "metric_value",
+			Help:      "Current value of a productivity metric, by name.",
+		}, []string{"metric"}),
+		durationHisto: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "vendatta",
+			Subsystem: "usage",
+			Name:      "pattern_duration_seconds",
+			Help:      "Distribution of observed usage pattern durations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"pattern"}),
+	}
+	r.reg.MustRegister(r.eventsTotal, r.activeGauge, r.durationHisto)
+	return r
+}
+
+// Gatherer exposes the underlying prometheus.Gatherer so callers can wire
+// it into promhttp.HandlerFor or a Pushgateway push.
+func (r *Registry) Gatherer() prometheus.Gatherer { return r.reg }
+
+// Registerer exposes the underlying prometheus.Registerer for pushing via
+// the push package's Collector() method.
+func (r *Registry) Registerer() prometheus.Registerer { return r.reg }
+
+// ObserveEvent records one usage event against the events_total counter.
+func (r *Registry) ObserveEvent(category string) {
+	r.eventsTotal.WithLabelValues(category).Inc()
+}
+
+// SetMetric records the current value of a named productivity metric,
+// as produced by UsageMetricsCommand.
+func (r *Registry) SetMetric(name string, value float64) {
+	r.activeGauge.WithLabelValues(name).Set(value)
+}
+
+// ObservePatternDuration records a single usage-pattern duration sample,
+// as produced by UsagePatternsCommand.
+func (r *Registry) ObservePatternDuration(pattern string, seconds float64) {
+	r.durationHisto.WithLabelValues(pattern).Observe(seconds)
+}
+
+// ApplySamples feeds a batch of metric samples (e.g. the output of
+// UsageMetricsCommand) into the gauge collector in one pass.
+func (r *Registry) ApplySamples(samples []Sample) {
+	for _, s := range samples {
+		r.SetMetric(s.Name, s.Value)
+	}
+}
+
+// Snapshot reads back the current value of every metric gauge, keyed by
+// metric name. It is used by reporters (e.g. the InfluxDB reporter) that
+// need to export the current state rather than scrape it over HTTP.
+func (r *Registry) Snapshot() map[string]float64 {
+	out := make(map[string]float64)
+	metricCh := make(chan prometheus.Metric)
+	go func() {
+		r.activeGauge.Collect(metricCh)
+		close(metricCh)
+	}()
+	for m := range metricCh {
+		var d dto.Metric
+		if err := m.Write(&d); err != nil {
+			continue
+		}
+		var name string
+		for _, l := range d.GetLabel() {
+			if l.GetName() == "metric" {
+				name = l.GetValue()
+			}
+		}
+		out[name] = d.GetGauge().GetValue()
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRegistryObserveEvent(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveEvent("commit")
+	r.ObserveEvent("commit")
+
+	want := `
+		# HELP vendatta_usage_events_total Total number of usage events recorded, by category.
+		# TYPE vendatta_usage_events_total counter
+		vendatta_usage_events_total{category="commit"} 2
+	`
+	if err := testutil.CollectAndCompare(r.eventsTotal, strings.NewReader(want), "vendatta_usage_events_total"); err != nil {
+		t.Fatalf("unexpected collected metrics:\n%s", err)
+	}
+}
+
+func TestRegistryApplySamples(t *testing.T) {
+	r := NewRegistry()
+	r.ApplySamples([]Sample{{Name: "focus_score", Value: 0.75}})
+
+	want := `
+		# HELP vendatta_usage_metric_value Current value of a productivity metric, by name.
+		# TYPE vendatta_usage_metric_value gauge
+		vendatta_usage_metric_value{metric="focus_score"} 0.75
+	`
+	if err := testutil.CollectAndCompare(r.activeGauge, strings.NewReader(want), "vendatta_usage_metric_value"); err != nil {
+		t.Fatalf("unexpected collected metrics:\n%s", err)
+	}
+}
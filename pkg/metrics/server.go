@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/urfave/cli"
+)
+
+// ServeCommand exposes /metrics over HTTP for scraping from reg, the
+// same Registry instance the usage commands and the InfluxDB reporter
+// populate, so a fresh, empty registry doesn't shadow real data. It
+// blocks until the server exits with an error.
+func ServeCommand(reg *Registry) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(reg.Gatherer(), promhttp.HandlerOpts{}))
+
+		addr := c.String("listen-address")
+		if addr == "" {
+			addr = ":9100"
+		}
+		return http.ListenAndServe(addr, mux)
+	}
+}
+
+// PushSnapshot pushes the current contents of reg to a Prometheus
+// Pushgateway under the given job name. This is how short-lived CLI runs
+// (as opposed to the long-running `metrics serve`) get their samples
+// into Prometheus, since nothing is around afterwards to be scraped.
+func PushSnapshot(gatewayURL, job string, reg *Registry) error {
+	if gatewayURL == "" {
+		return fmt.Errorf("metrics: pushgateway URL is required")
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return push.New(gatewayURL, job).
+		Grouping("instance", hostname).
+		Collector(reg.eventsTotal).
+		Collector(reg.activeGauge).
+		Collector(reg.durationHisto).
+		Push()
+}
+
+// Flags are the global flags registered by the metrics subsystem: the
+// listen address for `metrics serve` and the `--pushgateway` opt-in used
+// by the usage commands to push a snapshot before exiting.
+var Flags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "listen-address",
+		Usage: "Address for `metrics serve` to listen on",
+		Value: ":9100",
+	},
+	cli.StringFlag{
+		Name:  "pushgateway",
+		Usage: "Prometheus Pushgateway URL to push a metrics snapshot to before exiting",
+	},
+}